@@ -0,0 +1,475 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TransactionType identifies whether a transaction exercises vested options
+// or sells previously-exercised shares.
+type TransactionType string
+
+const (
+	TransactionExercise TransactionType = "exercise"
+	TransactionSell     TransactionType = "sell"
+)
+
+// defaultHoldingPeriodDays is the number of days after exercise required for
+// a sale to qualify for long-term capital gains treatment.
+const defaultHoldingPeriodDays = 365
+
+// Transaction represents a single exercise or sale event against a grant.
+type Transaction struct {
+	GrantName     string
+	Type          TransactionType
+	Date          time.Time
+	Shares        int
+	PricePerShare float64
+}
+
+// lot is a block of shares that vested together. CostBasis is the per-share
+// strike price paid to exercise the lot. Remaining tracks how many shares in
+// the lot have not yet been exercised.
+type lot struct {
+	grantName string
+	vestDate  time.Time
+	costBasis float64
+	remaining int
+}
+
+// exercisedParcel is the portion of a lot that has been exercised and is now
+// held as shares awaiting sale. Remaining tracks how many shares have not yet
+// been sold.
+type exercisedParcel struct {
+	grantName     string
+	vestDate      time.Time
+	exerciseDate  time.Time
+	exercisePrice float64
+	shares        int
+	remaining     int
+}
+
+// ExerciseRecord is one line of the per-lot ledger produced when vested
+// shares are exercised: the spread over the strike price is ordinary income.
+type ExerciseRecord struct {
+	GrantName      string
+	VestDate       time.Time
+	ExerciseDate   time.Time
+	Shares         int
+	StrikePrice    float64
+	ExercisePrice  float64
+	OrdinaryIncome float64
+}
+
+// SaleRecord is one line of the per-lot ledger produced when held shares are
+// sold, disposing of the oldest exercised lots first.
+type SaleRecord struct {
+	GrantName    string
+	VestDate     time.Time
+	ExerciseDate time.Time
+	SellDate     time.Time
+	Shares       int
+	CostBasis    float64
+	SellPrice    float64
+	LongTerm     bool
+}
+
+// Proceeds returns the gross sale proceeds for this record.
+func (s SaleRecord) Proceeds() float64 {
+	return float64(s.Shares) * s.SellPrice
+}
+
+// Gain returns the capital gain (or loss, if negative) for this record.
+func (s SaleRecord) Gain() float64 {
+	return float64(s.Shares) * (s.SellPrice - s.CostBasis)
+}
+
+// loadTransactions reads a CSV of {grant_name, type, date, shares,
+// price_per_share} rows, in that order, with a header row.
+func loadTransactions(filePath string) ([]Transaction, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	var transactions []Transaction
+	headerSkipped := false
+	lineNumber := 0
+
+	for {
+		lineNumber++
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading csv line %d: %w", lineNumber, err)
+		}
+
+		if !headerSkipped {
+			headerSkipped = true
+			continue // Skip the header row
+		}
+
+		if len(record) != 5 {
+			return nil, fmt.Errorf("invalid number of columns in CSV row %d (expected 5): %v", lineNumber, record)
+		}
+
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+
+		transactionType := TransactionType(record[1])
+		if transactionType != TransactionExercise && transactionType != TransactionSell {
+			return nil, fmt.Errorf("invalid transaction type on line %d ('%s'): must be %q or %q", lineNumber, record[1], TransactionExercise, TransactionSell)
+		}
+
+		date, err := time.Parse(DateFormat, record[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction date format on line %d ('%s', expected %s): %w", lineNumber, record[2], DateFormat, err)
+		}
+
+		shares, err := strconv.Atoi(record[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid shares on line %d ('%s'): %w", lineNumber, record[3], err)
+		}
+		if shares <= 0 {
+			return nil, fmt.Errorf("shares must be positive on line %d ('%s')", lineNumber, record[3])
+		}
+
+		pricePerShare, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price per share on line %d ('%s'): %w", lineNumber, record[4], err)
+		}
+
+		transactions = append(transactions, Transaction{
+			GrantName:     record[0],
+			Type:          transactionType,
+			Date:          date,
+			Shares:        shares,
+			PricePerShare: pricePerShare,
+		})
+	}
+
+	return transactions, nil
+}
+
+// reconcileTransactions walks transactions in chronological order and
+// applies them FIFO against each grant's vesting lots, producing a ledger of
+// exercises (ordinary income) and sales (capital gains). It rejects any
+// transaction that exceeds the shares available as of its date.
+func reconcileTransactions(grants []Grant, transactions []Transaction, holdingPeriodDays int) ([]ExerciseRecord, []SaleRecord, error) {
+	lotsByGrant := make(map[string][]*lot)
+	for _, grant := range grants {
+		for _, event := range calculateVestingSchedule(grant) {
+			if event.VestedShares <= 0 {
+				continue
+			}
+			lotsByGrant[grant.Name] = append(lotsByGrant[grant.Name], &lot{
+				grantName: grant.Name,
+				vestDate:  event.Date,
+				costBasis: grant.StrikePrice,
+				remaining: event.VestedShares,
+			})
+		}
+	}
+	parcelsByGrant := make(map[string][]*exercisedParcel)
+
+	sorted := make([]Transaction, len(transactions))
+	copy(sorted, transactions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	var exerciseRecords []ExerciseRecord
+	var saleRecords []SaleRecord
+
+	for _, tx := range sorted {
+		if _, ok := lotsByGrant[tx.GrantName]; !ok {
+			return nil, nil, fmt.Errorf("transaction references unknown grant %q", tx.GrantName)
+		}
+
+		switch tx.Type {
+		case TransactionExercise:
+			lots := lotsByGrant[tx.GrantName]
+			available := 0
+			for _, l := range lots {
+				if !l.vestDate.After(tx.Date) {
+					available += l.remaining
+				}
+			}
+			if available < tx.Shares {
+				return nil, nil, fmt.Errorf("cannot exercise %d shares of %q on %s: only %d vested and unexercised", tx.Shares, tx.GrantName, tx.Date.Format(DateFormat), available)
+			}
+
+			remaining := tx.Shares
+			for _, l := range lots {
+				if remaining == 0 {
+					break
+				}
+				if l.vestDate.After(tx.Date) || l.remaining == 0 {
+					continue
+				}
+				take := min(l.remaining, remaining)
+				l.remaining -= take
+				remaining -= take
+
+				parcelsByGrant[tx.GrantName] = append(parcelsByGrant[tx.GrantName], &exercisedParcel{
+					grantName:     tx.GrantName,
+					vestDate:      l.vestDate,
+					exerciseDate:  tx.Date,
+					exercisePrice: tx.PricePerShare,
+					shares:        take,
+					remaining:     take,
+				})
+				exerciseRecords = append(exerciseRecords, ExerciseRecord{
+					GrantName:      tx.GrantName,
+					VestDate:       l.vestDate,
+					ExerciseDate:   tx.Date,
+					Shares:         take,
+					StrikePrice:    l.costBasis,
+					ExercisePrice:  tx.PricePerShare,
+					OrdinaryIncome: float64(take) * (tx.PricePerShare - l.costBasis),
+				})
+			}
+
+		case TransactionSell:
+			parcels := parcelsByGrant[tx.GrantName]
+			available := 0
+			for _, p := range parcels {
+				if !p.exerciseDate.After(tx.Date) {
+					available += p.remaining
+				}
+			}
+			if available < tx.Shares {
+				return nil, nil, fmt.Errorf("cannot sell %d shares of %q on %s: only %d held and exercised", tx.Shares, tx.GrantName, tx.Date.Format(DateFormat), available)
+			}
+
+			remaining := tx.Shares
+			for _, p := range parcels {
+				if remaining == 0 {
+					break
+				}
+				if p.exerciseDate.After(tx.Date) || p.remaining == 0 {
+					continue
+				}
+				take := min(p.remaining, remaining)
+				p.remaining -= take
+				remaining -= take
+
+				holdingDays := int(tx.Date.Sub(p.exerciseDate).Hours() / 24)
+				saleRecords = append(saleRecords, SaleRecord{
+					GrantName:    tx.GrantName,
+					VestDate:     p.vestDate,
+					ExerciseDate: p.exerciseDate,
+					SellDate:     tx.Date,
+					Shares:       take,
+					CostBasis:    p.exercisePrice,
+					SellPrice:    tx.PricePerShare,
+					LongTerm:     holdingDays >= holdingPeriodDays,
+				})
+			}
+		}
+	}
+
+	return exerciseRecords, saleRecords, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// printLotLedger prints every exercise and sale record in chronological
+// order, one line per lot.
+func printLotLedger(exercises []ExerciseRecord, sales []SaleRecord) {
+	fmt.Printf("\n%-20s %-10s %-12s %-12s %-8s %-10s %-14s\n", "Grant Name", "Action", "Vest Date", "Event Date", "Shares", "Price", "Gain/Income")
+	fmt.Println(strings.Repeat("-", 92))
+
+	type row struct {
+		date time.Time
+		text string
+	}
+	var rows []row
+	for _, e := range exercises {
+		rows = append(rows, row{
+			date: e.ExerciseDate,
+			text: fmt.Sprintf("%-20s %-10s %-12s %-12s %-8d %-10.2f $%-13.2f", e.GrantName, "exercise", e.VestDate.Format(DateFormat), e.ExerciseDate.Format(DateFormat), e.Shares, e.ExercisePrice, e.OrdinaryIncome),
+		})
+	}
+	for _, s := range sales {
+		term := "short-term"
+		if s.LongTerm {
+			term = "long-term"
+		}
+		rows = append(rows, row{
+			date: s.SellDate,
+			text: fmt.Sprintf("%-20s %-10s %-12s %-12s %-8d %-10.2f $%-13.2f (%s)", s.GrantName, "sell", s.VestDate.Format(DateFormat), s.SellDate.Format(DateFormat), s.Shares, s.SellPrice, s.Gain(), term),
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].date.Before(rows[j].date) })
+	for _, r := range rows {
+		fmt.Println(r.text)
+	}
+}
+
+// printRealizedVsUnrealized reports, per grant, the value already locked in
+// through exercises and sales (realized) as of endDate, alongside the
+// intrinsic value still sitting in shares that haven't been sold: vested-but-
+// unexercised options (valued against the strike price) and exercised-but-
+// unsold shares (valued against the FMV paid at exercise, since the spread up
+// to that FMV was already booked as ordinary income).
+func printRealizedVsUnrealized(grants []Grant, exercises []ExerciseRecord, sales []SaleRecord, exerciseValue float64, endDate time.Time) {
+	vestedByGrant := make(map[string]int)
+	for _, grant := range grants {
+		vested := 0
+		for _, event := range calculateVestingSchedule(grant) {
+			if !event.Date.After(endDate) {
+				vested += event.VestedShares
+			}
+		}
+		vestedByGrant[grant.Name] = vested
+	}
+
+	exercisedByGrant := make(map[string]int)
+	realizedByGrant := make(map[string]float64)
+	for _, e := range exercises {
+		if e.ExerciseDate.After(endDate) {
+			continue
+		}
+		exercisedByGrant[e.GrantName] += e.Shares
+		realizedByGrant[e.GrantName] += e.OrdinaryIncome
+	}
+	for _, s := range sales {
+		if s.SellDate.After(endDate) {
+			continue
+		}
+		realizedByGrant[s.GrantName] += s.Gain()
+	}
+
+	strikeByGrant := make(map[string]float64)
+	for _, grant := range grants {
+		strikeByGrant[grant.Name] = grant.StrikePrice
+	}
+
+	// Consume exercised lots (oldest first, mirroring reconcileTransactions'
+	// FIFO order) by shares already sold, so whatever's left is still held.
+	type exercisedLot struct {
+		shares        int
+		exercisePrice float64
+	}
+	exercisedLotsByGrant := make(map[string][]exercisedLot)
+	for _, e := range exercises {
+		if e.ExerciseDate.After(endDate) {
+			continue
+		}
+		exercisedLotsByGrant[e.GrantName] = append(exercisedLotsByGrant[e.GrantName], exercisedLot{shares: e.Shares, exercisePrice: e.ExercisePrice})
+	}
+	soldByGrant := make(map[string]int)
+	for _, s := range sales {
+		if s.SellDate.After(endDate) {
+			continue
+		}
+		soldByGrant[s.GrantName] += s.Shares
+	}
+
+	heldUnrealizedByGrant := make(map[string]float64)
+	for name, lots := range exercisedLotsByGrant {
+		toConsume := soldByGrant[name]
+		value := 0.0
+		for _, l := range lots {
+			shares := l.shares
+			if toConsume > 0 {
+				consumed := min(toConsume, shares)
+				shares -= consumed
+				toConsume -= consumed
+			}
+			if shares > 0 {
+				value += float64(shares) * (exerciseValue - l.exercisePrice)
+			}
+		}
+		if value < 0 {
+			value = 0
+		}
+		heldUnrealizedByGrant[name] = value
+	}
+
+	fmt.Printf("\n%-20s %-16s %-22s %-16s\n", "Grant Name", "Realized Value", "Unrealized (Options)", "Unrealized (Held)")
+	fmt.Println(strings.Repeat("-", 76))
+	totalRealized, totalUnexercisedUnrealized, totalHeldUnrealized := 0.0, 0.0, 0.0
+	for _, grant := range grants {
+		unexercisedShares := vestedByGrant[grant.Name] - exercisedByGrant[grant.Name]
+		if unexercisedShares < 0 {
+			unexercisedShares = 0
+		}
+		unexercisedValue := float64(unexercisedShares) * (exerciseValue - strikeByGrant[grant.Name])
+		if unexercisedValue < 0 {
+			unexercisedValue = 0
+		}
+		heldValue := heldUnrealizedByGrant[grant.Name]
+		realizedValue := realizedByGrant[grant.Name]
+
+		fmt.Printf("%-20s $%-15.2f $%-21.2f $%-15.2f\n", grant.Name, realizedValue, unexercisedValue, heldValue)
+		totalRealized += realizedValue
+		totalUnexercisedUnrealized += unexercisedValue
+		totalHeldUnrealized += heldValue
+	}
+	fmt.Println(strings.Repeat("-", 76))
+	fmt.Printf("%-20s $%-15.2f $%-21.2f $%-15.2f\n", "Total", totalRealized, totalUnexercisedUnrealized, totalHeldUnrealized)
+}
+
+// printAnnualSummary aggregates ordinary income, short/long-term gains, and
+// proceeds by calendar year of the relevant event.
+func printAnnualSummary(exercises []ExerciseRecord, sales []SaleRecord) {
+	type yearTotals struct {
+		ordinaryIncome float64
+		shortTermGain  float64
+		longTermGain   float64
+		proceeds       float64
+	}
+	totalsByYear := make(map[int]*yearTotals)
+	yearOf := func(y int) *yearTotals {
+		t, ok := totalsByYear[y]
+		if !ok {
+			t = &yearTotals{}
+			totalsByYear[y] = t
+		}
+		return t
+	}
+
+	for _, e := range exercises {
+		yearOf(e.ExerciseDate.Year()).ordinaryIncome += e.OrdinaryIncome
+	}
+	for _, s := range sales {
+		t := yearOf(s.SellDate.Year())
+		t.proceeds += s.Proceeds()
+		if s.LongTerm {
+			t.longTermGain += s.Gain()
+		} else {
+			t.shortTermGain += s.Gain()
+		}
+	}
+
+	years := make([]int, 0, len(totalsByYear))
+	for y := range totalsByYear {
+		years = append(years, y)
+	}
+	sort.Ints(years)
+
+	fmt.Printf("\n%-6s %-16s %-14s %-14s %-12s\n", "Year", "Ordinary Income", "ST Gain", "LT Gain", "Proceeds")
+	fmt.Println(strings.Repeat("-", 64))
+	for _, y := range years {
+		t := totalsByYear[y]
+		fmt.Printf("%-6d $%-15.2f $%-13.2f $%-13.2f $%-11.2f\n", y, t.ordinaryIncome, t.shortTermGain, t.longTermGain, t.proceeds)
+	}
+}
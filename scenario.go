@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultPostTerminationExerciseDays is the PTE window used when neither the
+// grant's CSV row nor -pte-days specifies one.
+const defaultPostTerminationExerciseDays = 90
+
+// GrantScenario is the per-grant outcome of simulating a termination date
+// and/or early exercise against a grant's vesting schedule.
+type GrantScenario struct {
+	Name              string
+	VestedExercisable int
+	VestedLapsed      int
+	ForfeitedUnvested int
+	EarlyExercised    int
+}
+
+// effectiveSchedule returns the vesting schedule that the main intrinsic-value
+// report should use for grant. When earlyExercise is active and the grant is
+// EarlyExercisable, the whole Shares count is treated as vested at GrantDate,
+// per the early-exercise/83(b) simulation; otherwise it's the grant's normal
+// schedule.
+func effectiveSchedule(grant Grant, earlyExercise bool) []VestingEvent {
+	if earlyExercise && grant.EarlyExercisable {
+		return []VestingEvent{{Date: grant.GrantDate, VestedShares: grant.Shares}}
+	}
+	return calculateVestingSchedule(grant)
+}
+
+// vestedSharesBefore sums VestedShares for every event on or before asOf.
+func vestedSharesBefore(schedule []VestingEvent, asOf time.Time) int {
+	vested := 0
+	for _, event := range schedule {
+		if !event.Date.After(asOf) {
+			vested += event.VestedShares
+		}
+	}
+	return vested
+}
+
+// computeGrantScenario simulates a leaver and/or early-exercise scenario for
+// a single grant as of asOfDate. terminationDate is nil if the holder has
+// not (in this scenario) left the company.
+func computeGrantScenario(grant Grant, schedule []VestingEvent, asOfDate time.Time, terminationDate *time.Time, pteDaysDefault int, earlyExercise bool) GrantScenario {
+	scenario := GrantScenario{Name: grant.Name}
+
+	if earlyExercise && grant.EarlyExercisable {
+		// All shares were exercised at GrantDate, so the unvested portion is
+		// held but repurchasable by the company until it vests (or is
+		// forfeited on termination).
+		scenario.EarlyExercised = grant.Shares
+		vestedAsOf := vestedSharesBefore(schedule, asOfDate)
+		repurchasable := grant.Shares - vestedAsOf
+		if repurchasable < 0 {
+			repurchasable = 0
+		}
+
+		if terminationDate != nil {
+			vestedAtTermination := vestedSharesBefore(schedule, *terminationDate)
+			forfeited := grant.Shares - vestedAtTermination
+			if forfeited < 0 {
+				forfeited = 0
+			}
+			scenario.ForfeitedUnvested = forfeited
+			scenario.VestedExercisable = grant.Shares - forfeited
+			return scenario
+		}
+
+		scenario.VestedExercisable = grant.Shares - repurchasable
+		return scenario
+	}
+
+	if terminationDate != nil {
+		vestedAtTermination := vestedSharesBefore(schedule, *terminationDate)
+		forfeited := grant.Shares - vestedAtTermination
+		if forfeited < 0 {
+			forfeited = 0
+		}
+		scenario.ForfeitedUnvested = forfeited
+
+		pteDays := grant.PostTerminationExerciseDays
+		if pteDays <= 0 {
+			pteDays = pteDaysDefault
+		}
+		pteDeadline := terminationDate.AddDate(0, 0, pteDays)
+
+		if asOfDate.After(pteDeadline) {
+			scenario.VestedLapsed = vestedAtTermination
+		} else {
+			scenario.VestedExercisable = vestedAtTermination
+		}
+		return scenario
+	}
+
+	scenario.VestedExercisable = vestedSharesBefore(schedule, asOfDate)
+	return scenario
+}
+
+// printScenarioSummary prints the per-grant leaver / early-exercise scenario
+// columns: vested & exercisable, vested & lapsed (PTE expired), forfeited
+// unvested, and shares early-exercised (repurchasable until vested).
+func printScenarioSummary(scenarios []GrantScenario) {
+	fmt.Printf("\n%-20s %-18s %-22s %-18s %-16s\n", "Grant Name", "Vested/Exercisable", "Vested/Lapsed (PTE)", "Forfeited Unvested", "Early-Exercised")
+	fmt.Println(strings.Repeat("-", 96))
+
+	totalExercisable, totalLapsed, totalForfeited, totalEarlyExercised := 0, 0, 0, 0
+	for _, s := range scenarios {
+		fmt.Printf("%-20s %-18d %-22d %-18d %-16d\n", s.Name, s.VestedExercisable, s.VestedLapsed, s.ForfeitedUnvested, s.EarlyExercised)
+		totalExercisable += s.VestedExercisable
+		totalLapsed += s.VestedLapsed
+		totalForfeited += s.ForfeitedUnvested
+		totalEarlyExercised += s.EarlyExercised
+	}
+	fmt.Println(strings.Repeat("-", 96))
+	fmt.Printf("%-20s %-18d %-22d %-18d %-16d\n", "Total", totalExercisable, totalLapsed, totalForfeited, totalEarlyExercised)
+}
@@ -0,0 +1,151 @@
+package main
+
+import "time"
+
+// addMonthsClamped adds months to t, clamping the day-of-month to the last
+// day of the target month instead of letting it overflow into the month
+// after (time.Time.AddDate's behavior). This is what lets a Jan-31 grant
+// vest on Feb 28/29, Mar 31, etc. rather than rolling over to Mar 2/3.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	firstOfTargetMonth := firstOfMonth.AddDate(0, months, 0)
+	lastDayOfTargetMonth := firstOfTargetMonth.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDayOfTargetMonth {
+		day = lastDayOfTargetMonth
+	}
+	return time.Date(firstOfTargetMonth.Year(), firstOfTargetMonth.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// ScheduleType identifies which VestingScheduler implementation governs a grant.
+type ScheduleType string
+
+const (
+	// ScheduleGraded vests shares monthly after the cliff, with a lump sum at
+	// the cliff date covering the months already elapsed. This is the
+	// historical default and is used when the CSV omits the column.
+	ScheduleGraded ScheduleType = "graded"
+	// ScheduleContinuous vests shares linearly on a daily basis from the
+	// grant date through the end of the vesting period. There is no cliff.
+	ScheduleContinuous ScheduleType = "continuous"
+	// ScheduleDelayed vests nothing until the final day of the vesting
+	// period, at which point 100% of the shares vest at once.
+	ScheduleDelayed ScheduleType = "delayed"
+	// ScheduleCliff vests nothing until the cliff date, at which point 100%
+	// of the shares vest at once. There is no further accrual afterwards.
+	ScheduleCliff ScheduleType = "cliff"
+)
+
+// defaultScheduleType is used when a grant's CSV row does not specify a
+// ScheduleType, preserving the behavior of older CSV files.
+const defaultScheduleType = ScheduleGraded
+
+// VestingScheduler computes the sequence of vesting events for a grant.
+type VestingScheduler interface {
+	Schedule(grant Grant) []VestingEvent
+}
+
+// schedulerFor returns the VestingScheduler implementation for the given
+// ScheduleType, falling back to the graded scheduler for an unset or
+// unrecognized type.
+func schedulerFor(scheduleType ScheduleType) VestingScheduler {
+	switch scheduleType {
+	case ScheduleContinuous:
+		return continuousScheduler{}
+	case ScheduleDelayed:
+		return delayedScheduler{}
+	case ScheduleCliff:
+		return cliffScheduler{}
+	default:
+		return gradedScheduler{}
+	}
+}
+
+// gradedScheduler vests sharesPerInterval shares on each monthly anniversary
+// of the grant date after the cliff, with any shares that accrued during the
+// cliff period vesting in a single lump sum on the cliff date.
+type gradedScheduler struct{}
+
+func (gradedScheduler) Schedule(grant Grant) []VestingEvent {
+	var schedule []VestingEvent
+	totalVestingMonths := grant.VestingMonths
+	sharesPerInterval := grant.Shares / totalVestingMonths
+
+	accumulatedShares := grant.CliffMonths * sharesPerInterval
+	if grant.CliffMonths >= totalVestingMonths {
+		// Degenerate cliff-only case: nothing vests monthly afterwards, so
+		// the cliff lump sum must cover 100% of the shares itself.
+		accumulatedShares = grant.Shares
+	}
+	if grant.CliffMonths > 0 {
+		schedule = append(schedule, VestingEvent{
+			// Vest on the same calendar day each month rather than every 30
+			// days, so e.g. a Jan 31 grant with a 1-month cliff vests Feb 28/29.
+			Date:         addMonthsClamped(grant.GrantDate, grant.CliffMonths),
+			VestedShares: accumulatedShares,
+		})
+	}
+	for i := grant.CliffMonths + 1; i <= totalVestingMonths; i++ {
+		vestingDate := addMonthsClamped(grant.GrantDate, i)
+		vestedShares := sharesPerInterval
+		if i == totalVestingMonths {
+			vestedShares = grant.Shares - accumulatedShares // Ensure all shares are vested by the end
+		}
+		accumulatedShares += vestedShares
+		schedule = append(schedule, VestingEvent{
+			Date:         vestingDate,
+			VestedShares: vestedShares,
+		})
+	}
+
+	return schedule
+}
+
+// continuousScheduler vests one event per day, linearly, from the grant date
+// through the end of the vesting period. There is no cliff: the cliff field
+// is ignored.
+type continuousScheduler struct{}
+
+func (continuousScheduler) Schedule(grant Grant) []VestingEvent {
+	endDate := addMonthsClamped(grant.GrantDate, grant.VestingMonths)
+	totalDays := int(endDate.Sub(grant.GrantDate).Hours() / 24)
+	if totalDays <= 0 {
+		return []VestingEvent{{Date: endDate, VestedShares: grant.Shares}}
+	}
+
+	schedule := make([]VestingEvent, 0, totalDays)
+	accumulatedShares := 0
+	for day := 1; day <= totalDays; day++ {
+		vestingDate := grant.GrantDate.AddDate(0, 0, day)
+		vestedShares := grant.Shares / totalDays
+		if day == totalDays {
+			vestedShares = grant.Shares - accumulatedShares // Ensure all shares are vested by the end
+		}
+		accumulatedShares += vestedShares
+		schedule = append(schedule, VestingEvent{
+			Date:         vestingDate,
+			VestedShares: vestedShares,
+		})
+	}
+	return schedule
+}
+
+// delayedScheduler vests nothing until the end of the vesting period, at
+// which point all shares vest at once.
+type delayedScheduler struct{}
+
+func (delayedScheduler) Schedule(grant Grant) []VestingEvent {
+	endDate := addMonthsClamped(grant.GrantDate, grant.VestingMonths)
+	return []VestingEvent{{Date: endDate, VestedShares: grant.Shares}}
+}
+
+// cliffScheduler vests nothing until the cliff date, at which point all
+// shares vest at once. A grant with CliffMonths equal to VestingMonths is a
+// degenerate, cliff-only schedule and behaves the same as delayedScheduler.
+type cliffScheduler struct{}
+
+func (cliffScheduler) Schedule(grant Grant) []VestingEvent {
+	cliffDate := addMonthsClamped(grant.GrantDate, grant.CliffMonths)
+	return []VestingEvent{{Date: cliffDate, VestedShares: grant.Shares}}
+}
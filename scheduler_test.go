@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(DateFormat, value)
+	if err != nil {
+		t.Fatalf("parsing date %q: %v", value, err)
+	}
+	return parsed
+}
+
+func TestGradedSchedulerMonthEndClamping(t *testing.T) {
+	grant := Grant{
+		Name:          "jan31",
+		Shares:        1200,
+		VestingMonths: 12,
+		CliffMonths:   1,
+		GrantDate:     mustDate(t, "2024-01-31"),
+	}
+
+	schedule := gradedScheduler{}.Schedule(grant)
+
+	wantDates := []string{
+		"2024-02-29", // cliff: Jan 31 + 1 month clamps to Feb 29 (2024 is a leap year)
+		"2024-03-31",
+		"2024-04-30",
+		"2024-05-31",
+		"2024-06-30",
+		"2024-07-31",
+		"2024-08-31",
+		"2024-09-30",
+		"2024-10-31",
+		"2024-11-30",
+		"2024-12-31",
+		"2025-01-31",
+	}
+	if len(schedule) != len(wantDates) {
+		t.Fatalf("got %d vesting events, want %d", len(schedule), len(wantDates))
+	}
+	for i, want := range wantDates {
+		if got := schedule[i].Date.Format(DateFormat); got != want {
+			t.Errorf("event %d: got date %s, want %s", i, got, want)
+		}
+	}
+
+	totalVested := 0
+	for _, event := range schedule {
+		totalVested += event.VestedShares
+	}
+	if totalVested != grant.Shares {
+		t.Errorf("total vested shares = %d, want %d", totalVested, grant.Shares)
+	}
+}
+
+func TestGradedSchedulerZeroCliff(t *testing.T) {
+	grant := Grant{
+		Name:          "no-cliff",
+		Shares:        1000,
+		VestingMonths: 4,
+		CliffMonths:   0,
+		GrantDate:     mustDate(t, "2024-03-15"),
+	}
+
+	schedule := gradedScheduler{}.Schedule(grant)
+
+	if len(schedule) != 4 {
+		t.Fatalf("got %d vesting events, want 4", len(schedule))
+	}
+	if schedule[0].Date.Format(DateFormat) != "2024-04-15" {
+		t.Errorf("first vesting event date = %s, want 2024-04-15 (no cliff lump sum)", schedule[0].Date.Format(DateFormat))
+	}
+
+	totalVested := 0
+	for _, event := range schedule {
+		totalVested += event.VestedShares
+	}
+	if totalVested != grant.Shares {
+		t.Errorf("total vested shares = %d, want %d", totalVested, grant.Shares)
+	}
+}
+
+func TestGradedSchedulerCliffEqualsVesting(t *testing.T) {
+	grant := Grant{
+		Name:          "cliff-only",
+		Shares:        100,
+		VestingMonths: 48,
+		CliffMonths:   48,
+		GrantDate:     mustDate(t, "2024-01-01"),
+	}
+
+	schedule := gradedScheduler{}.Schedule(grant)
+
+	if len(schedule) != 1 {
+		t.Fatalf("got %d vesting events, want 1 (cliff-only, degenerate case)", len(schedule))
+	}
+	if schedule[0].VestedShares != grant.Shares {
+		t.Errorf("cliff lump sum = %d shares, want all %d shares", schedule[0].VestedShares, grant.Shares)
+	}
+	wantDate := addMonthsClamped(grant.GrantDate, grant.CliffMonths).Format(DateFormat)
+	if got := schedule[0].Date.Format(DateFormat); got != wantDate {
+		t.Errorf("cliff date = %s, want %s", got, wantDate)
+	}
+}
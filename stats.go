@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// daysPerMonth approximates a month for duration-based stats (time-to-vest,
+// velocity windows) where a calendar-accurate AddDate isn't needed.
+const daysPerMonth = 30.44
+
+// irrLowerBound, irrUpperBound, and irrTolerance bound the bisection search
+// used by irr below.
+const (
+	irrLowerBound = -0.99
+	irrUpperBound = 10.0
+	irrTolerance  = 1e-7
+)
+
+// cashFlow is a single dated cash flow used to compute an IRR.
+type cashFlow struct {
+	date   time.Time
+	amount float64
+}
+
+// GrantStats holds the per-grant figures shown in the -stats report.
+type GrantStats struct {
+	Name                 string
+	IRR                  float64
+	VestedShares         int
+	TotalShares          int
+	SharePercent         float64
+	ValuePercent         float64
+	TimeToFullVestMonths float64
+}
+
+// PortfolioStats holds the aggregate figures shown in the -stats report,
+// modeled after the per-interval trade-stats reports used in backtesting
+// tooling.
+type PortfolioStats struct {
+	Grants                         []GrantStats
+	OverallIRR                     float64
+	TotalVestedShares              int
+	TotalShares                    int
+	TotalVestedValue               float64
+	VestingVelocityTrailing12PerMo float64
+	VestingVelocityNext12PerMo     float64
+	WeightedAvgRemainingMonths     float64
+	CliffRiskPercent               float64
+}
+
+// npv returns the net present value of flows discounted at rate, using an
+// Actual/365 day count measured from the date of flows[0]; flows need not be
+// sorted, and flows dated before flows[0] get a negative year fraction.
+func npv(flows []cashFlow, rate float64) float64 {
+	if len(flows) == 0 {
+		return 0
+	}
+	t0 := flows[0].date
+	total := 0.0
+	for _, f := range flows {
+		years := f.date.Sub(t0).Hours() / 24 / 365
+		total += f.amount / math.Pow(1+rate, years)
+	}
+	return total
+}
+
+// irr solves for the annualized rate that zeroes the NPV of flows via
+// bisection over [irrLowerBound, irrUpperBound]. It returns NaN if flows are
+// empty or all cash flows share the same sign (no sign change to bracket).
+func irr(flows []cashFlow) float64 {
+	if len(flows) == 0 {
+		return math.NaN()
+	}
+
+	allNonNegative, allNonPositive := true, true
+	for _, f := range flows {
+		if f.amount < 0 {
+			allNonNegative = false
+		}
+		if f.amount > 0 {
+			allNonPositive = false
+		}
+	}
+	if allNonNegative || allNonPositive {
+		return math.NaN()
+	}
+
+	lo, hi := irrLowerBound, irrUpperBound
+	npvLo, npvHi := npv(flows, lo), npv(flows, hi)
+	if npvLo*npvHi > 0 {
+		return math.NaN()
+	}
+
+	for i := 0; i < 200; i++ {
+		mid := (lo + hi) / 2
+		npvMid := npv(flows, mid)
+		if math.Abs(npvMid) < irrTolerance || (hi-lo) < irrTolerance {
+			return mid
+		}
+		if (npvMid > 0) == (npvLo > 0) {
+			lo, npvLo = mid, npvMid
+		} else {
+			hi, npvHi = mid, npvMid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// computePortfolioStats computes the -stats report for grants as of endDate
+// at the given exerciseValue.
+func computePortfolioStats(grants []Grant, exerciseValue float64, endDate time.Time) PortfolioStats {
+	var stats PortfolioStats
+	stats.Grants = make([]GrantStats, len(grants))
+
+	var overallFlows []cashFlow
+	totalUnvestedValue := 0.0
+	cliffRiskValue := 0.0
+	weightedRemainingMonths := 0.0
+	totalUnvestedShares := 0
+	trailingSharesVested := 0
+	nextSharesVesting := 0
+
+	for i, grant := range grants {
+		schedule := calculateVestingSchedule(grant)
+
+		vestedShares := 0
+		var flows []cashFlow
+		var lastVestDate time.Time
+		remainingUnvestedShares := 0
+		for _, event := range schedule {
+			if !event.Date.After(endDate) {
+				vestedShares += event.VestedShares
+				flow := cashFlow{date: event.Date, amount: -float64(event.VestedShares) * grant.StrikePrice}
+				flows = append(flows, flow)
+				overallFlows = append(overallFlows, flow)
+			} else {
+				remainingUnvestedShares += event.VestedShares
+			}
+			if event.Date.After(lastVestDate) {
+				lastVestDate = event.Date
+			}
+		}
+
+		vestedValue := float64(vestedShares) * exerciseValue
+		if len(flows) > 0 {
+			flows = append(flows, cashFlow{date: endDate, amount: vestedValue})
+			overallFlows = append(overallFlows, cashFlow{date: endDate, amount: vestedValue})
+		}
+
+		unvestedValue := float64(remainingUnvestedShares) * math.Max(0, exerciseValue-grant.StrikePrice)
+		totalUnvestedValue += unvestedValue
+		totalUnvestedShares += remainingUnvestedShares
+
+		for _, event := range schedule {
+			if event.Date.After(endDate) && !event.Date.After(endDate.AddDate(0, 0, 90)) {
+				cliffRiskValue += float64(event.VestedShares) * math.Max(0, exerciseValue-grant.StrikePrice)
+			}
+			if event.Date.After(endDate) {
+				remainingMonths := event.Date.Sub(endDate).Hours() / 24 / daysPerMonth
+				weightedRemainingMonths += remainingMonths * float64(event.VestedShares)
+			}
+		}
+
+		timeToFullVestMonths := 0.0
+		if lastVestDate.After(endDate) {
+			timeToFullVestMonths = lastVestDate.Sub(endDate).Hours() / 24 / daysPerMonth
+		}
+
+		stats.Grants[i] = GrantStats{
+			Name:                 grant.Name,
+			IRR:                  irr(flows),
+			VestedShares:         vestedShares,
+			TotalShares:          grant.Shares,
+			TimeToFullVestMonths: timeToFullVestMonths,
+		}
+		stats.TotalVestedShares += vestedShares
+		stats.TotalShares += grant.Shares
+		stats.TotalVestedValue += vestedValue
+
+		trailingStart := endDate.AddDate(0, -12, 0)
+		nextEnd := endDate.AddDate(0, 12, 0)
+		for _, event := range schedule {
+			if event.Date.After(trailingStart) && !event.Date.After(endDate) {
+				trailingSharesVested += event.VestedShares
+			}
+			if event.Date.After(endDate) && !event.Date.After(nextEnd) {
+				nextSharesVesting += event.VestedShares
+			}
+		}
+	}
+	stats.VestingVelocityTrailing12PerMo = float64(trailingSharesVested) / 12
+	stats.VestingVelocityNext12PerMo = float64(nextSharesVesting) / 12
+
+	for i := range stats.Grants {
+		if stats.TotalShares > 0 {
+			stats.Grants[i].SharePercent = 100 * float64(stats.Grants[i].TotalShares) / float64(stats.TotalShares)
+		}
+		vestedValue := float64(stats.Grants[i].VestedShares) * exerciseValue
+		if stats.TotalVestedValue > 0 {
+			stats.Grants[i].ValuePercent = 100 * vestedValue / stats.TotalVestedValue
+		}
+	}
+
+	stats.OverallIRR = irr(overallFlows)
+	if totalUnvestedShares > 0 {
+		stats.WeightedAvgRemainingMonths = weightedRemainingMonths / float64(totalUnvestedShares)
+	}
+	if totalUnvestedValue > 0 {
+		stats.CliffRiskPercent = 100 * cliffRiskValue / totalUnvestedValue
+	}
+
+	return stats
+}
+
+// MarshalJSON renders IRR as null instead of NaN, since encoding/json
+// rejects non-finite floats.
+func (g GrantStats) MarshalJSON() ([]byte, error) {
+	type alias GrantStats
+	var irrPtr *float64
+	if !math.IsNaN(g.IRR) {
+		irrPtr = &g.IRR
+	}
+	return json.Marshal(struct {
+		alias
+		IRR *float64 `json:"IRR"`
+	}{alias: alias(g), IRR: irrPtr})
+}
+
+// MarshalJSON renders OverallIRR as null instead of NaN, since encoding/json
+// rejects non-finite floats.
+func (p PortfolioStats) MarshalJSON() ([]byte, error) {
+	type alias PortfolioStats
+	var irrPtr *float64
+	if !math.IsNaN(p.OverallIRR) {
+		irrPtr = &p.OverallIRR
+	}
+	return json.Marshal(struct {
+		alias
+		OverallIRR *float64 `json:"OverallIRR"`
+	}{alias: alias(p), OverallIRR: irrPtr})
+}
+
+func formatIRR(rate float64) string {
+	if math.IsNaN(rate) {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.2f%%", rate*100)
+}
+
+// printPortfolioStats writes the -stats report in the requested format
+// (text or json) to stdout.
+func printPortfolioStats(stats PortfolioStats, format string) error {
+	switch format {
+	case "", "text":
+		fmt.Printf("\nPortfolio Statistics:\n")
+		fmt.Println(strings.Repeat("-", 70))
+		fmt.Printf("%-20s %-10s %-8s %-8s %-10s %-10s\n", "Grant Name", "IRR", "Shares%", "Value%", "Vested", "ToFull(mo)")
+		for _, g := range stats.Grants {
+			fmt.Printf("%-20s %-10s %-8.2f %-8.2f %-10d %-10.1f\n",
+				g.Name, formatIRR(g.IRR), g.SharePercent, g.ValuePercent, g.VestedShares, g.TimeToFullVestMonths)
+		}
+		fmt.Println(strings.Repeat("-", 70))
+		fmt.Printf("Overall IRR: %s\n", formatIRR(stats.OverallIRR))
+		fmt.Printf("Vesting velocity: %.2f shares/month (trailing 12mo), %.2f shares/month (next 12mo)\n", stats.VestingVelocityTrailing12PerMo, stats.VestingVelocityNext12PerMo)
+		fmt.Printf("Weighted-average remaining vesting: %.1f months\n", stats.WeightedAvgRemainingMonths)
+		fmt.Printf("Cliff risk (unvested value vesting within 90 days): %.2f%%\n", stats.CliffRiskPercent)
+		return nil
+
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+
+	default:
+		return fmt.Errorf("unsupported -stats-format %q (expected text or json)", format)
+	}
+}
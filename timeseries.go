@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PriceObservation is a single observed price on a given date, as read from
+// the -price-history CSV.
+type PriceObservation struct {
+	Date          time.Time
+	PricePerShare float64
+}
+
+// TimeSeriesPoint is one row of the merged vesting/price time series: the
+// portfolio's vested share count and intrinsic value as of Date.
+type TimeSeriesPoint struct {
+	Date                   time.Time
+	TotalVestedShares      int
+	Price                  float64
+	IntrinsicValuePerShare float64
+	AccumulatedValue       float64
+}
+
+// loadPriceHistory reads a CSV of {date, price_per_share} rows, in that
+// order, with a header row.
+func loadPriceHistory(filePath string) ([]PriceObservation, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	var observations []PriceObservation
+	headerSkipped := false
+	lineNumber := 0
+
+	for {
+		lineNumber++
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading csv line %d: %w", lineNumber, err)
+		}
+
+		if !headerSkipped {
+			headerSkipped = true
+			continue // Skip the header row
+		}
+
+		if len(record) != 2 {
+			return nil, fmt.Errorf("invalid number of columns in CSV row %d (expected 2): %v", lineNumber, record)
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+
+		date, err := time.Parse(DateFormat, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format on line %d ('%s', expected %s): %w", lineNumber, record[0], DateFormat, err)
+		}
+		price, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price per share on line %d ('%s'): %w", lineNumber, record[1], err)
+		}
+
+		observations = append(observations, PriceObservation{Date: date, PricePerShare: price})
+	}
+
+	sort.SliceStable(observations, func(i, j int) bool { return observations[i].Date.Before(observations[j].Date) })
+	return observations, nil
+}
+
+// computeTimeSeries walks the merged, chronologically-sorted stream of
+// vesting events (across all grants) and price observations, emitting one
+// TimeSeriesPoint per event date. The price carries forward from the last
+// observation seen (last-observation-carried-forward) until a new one
+// arrives; before the first observation, price is 0 and so is intrinsic
+// value, alongside printLatestVestingEventBefore's single-snapshot view.
+func computeTimeSeries(grants []Grant, prices []PriceObservation) []TimeSeriesPoint {
+	type mergedEvent struct {
+		date     time.Time
+		isPrice  bool
+		price    float64
+		grantIdx int
+		shares   int
+	}
+
+	var events []mergedEvent
+	for i, grant := range grants {
+		for _, event := range calculateVestingSchedule(grant) {
+			events = append(events, mergedEvent{date: event.Date, grantIdx: i, shares: event.VestedShares})
+		}
+	}
+	for _, p := range prices {
+		events = append(events, mergedEvent{date: p.Date, isPrice: true, price: p.PricePerShare})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].date.Before(events[j].date) })
+
+	vestedByGrant := make([]int, len(grants))
+	currentPrice := 0.0
+	havePrice := false
+
+	series := make([]TimeSeriesPoint, 0, len(events))
+	for _, e := range events {
+		if e.isPrice {
+			currentPrice = e.price
+			havePrice = true
+		} else {
+			vestedByGrant[e.grantIdx] += e.shares
+		}
+
+		totalVestedShares := 0
+		accumulatedValue := 0.0
+		for i, grant := range grants {
+			totalVestedShares += vestedByGrant[i]
+			if !havePrice {
+				continue
+			}
+			value := float64(vestedByGrant[i]) * (currentPrice - grant.StrikePrice)
+			if value > 0 {
+				accumulatedValue += value
+			}
+		}
+
+		intrinsicValuePerShare := 0.0
+		if totalVestedShares > 0 {
+			intrinsicValuePerShare = accumulatedValue / float64(totalVestedShares)
+		}
+
+		point := TimeSeriesPoint{
+			Date:                   e.date,
+			TotalVestedShares:      totalVestedShares,
+			Price:                  currentPrice,
+			IntrinsicValuePerShare: intrinsicValuePerShare,
+			AccumulatedValue:       accumulatedValue,
+		}
+		// Later events on the same date supersede the earlier, partial point.
+		if n := len(series); n > 0 && series[n-1].Date.Equal(e.date) {
+			series[n-1] = point
+		} else {
+			series = append(series, point)
+		}
+	}
+
+	return series
+}
+
+// printTimeSeries writes the series in the requested format (text, csv, or
+// json) to stdout.
+func printTimeSeries(series []TimeSeriesPoint, format string) error {
+	switch format {
+	case "", "text":
+		fmt.Printf("\n%-12s %-18s %-10s %-22s %-18s\n", "Date", "Total Vested", "Price", "Intrinsic Value/Share", "Accumulated Value")
+		fmt.Println(strings.Repeat("-", 82))
+		for _, point := range series {
+			fmt.Printf("%-12s %-18d $%-9.2f $%-21.2f $%-17.2f\n",
+				point.Date.Format(DateFormat), point.TotalVestedShares, point.Price, point.IntrinsicValuePerShare, point.AccumulatedValue)
+		}
+		return nil
+
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		defer writer.Flush()
+		if err := writer.Write([]string{"date", "total_vested_shares", "price", "intrinsic_value_per_share", "accumulated_value"}); err != nil {
+			return err
+		}
+		for _, point := range series {
+			record := []string{
+				point.Date.Format(DateFormat),
+				strconv.Itoa(point.TotalVestedShares),
+				strconv.FormatFloat(point.Price, 'f', 2, 64),
+				strconv.FormatFloat(point.IntrinsicValuePerShare, 'f', 2, 64),
+				strconv.FormatFloat(point.AccumulatedValue, 'f', 2, 64),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "json":
+		type jsonPoint struct {
+			Date                   string  `json:"date"`
+			TotalVestedShares      int     `json:"total_vested_shares"`
+			Price                  float64 `json:"price"`
+			IntrinsicValuePerShare float64 `json:"intrinsic_value_per_share"`
+			AccumulatedValue       float64 `json:"accumulated_value"`
+		}
+		points := make([]jsonPoint, len(series))
+		for i, point := range series {
+			points[i] = jsonPoint{
+				Date:                   point.Date.Format(DateFormat),
+				TotalVestedShares:      point.TotalVestedShares,
+				Price:                  point.Price,
+				IntrinsicValuePerShare: point.IntrinsicValuePerShare,
+				AccumulatedValue:       point.AccumulatedValue,
+			}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(points)
+
+	default:
+		return fmt.Errorf("unsupported -output format %q (expected text, csv, or json)", format)
+	}
+}
@@ -19,6 +19,13 @@ type Grant struct {
 	CliffMonths   int
 	VestingMonths int
 	GrantDate     time.Time
+	ScheduleType  ScheduleType
+
+	// PostTerminationExerciseDays overrides the default PTE window
+	// (-pte-days) for this grant; 0 means "use the default".
+	PostTerminationExerciseDays int
+	// EarlyExercisable marks the grant as eligible for -early-exercise.
+	EarlyExercisable bool
 }
 
 // VestingEvent represents a single vesting event.
@@ -35,6 +42,15 @@ func main() {
 	exerciseValuePtr := flag.Float64("exercise", 0.0, "Current exercise value per share (required)")
 	endDateStrPtr := flag.String("end-date", "", "Calculate vesting up to this date (YYYY-MM-DD) (required)")
 	printVestingSchedulePtr := flag.Bool("print-schedule", false, "Print the full vesting schedule for each grant")
+	transactionsFilePtr := flag.String("transactions", "", "Path to a CSV of exercise/sell transactions (optional)")
+	holdingPeriodDaysPtr := flag.Int("holding-period-days", defaultHoldingPeriodDays, "Days after exercise required for long-term capital gains treatment")
+	priceHistoryFilePtr := flag.String("price-history", "", "Path to a CSV of {date, price_per_share} for time-series valuation (optional)")
+	outputFormatPtr := flag.String("output", "text", "Output format for -price-history series: text, csv, or json")
+	statsPtr := flag.Bool("stats", false, "Print aggregate portfolio statistics (IRR, concentration, vesting velocity)")
+	statsFormatPtr := flag.String("stats-format", "text", "Output format for -stats: text or json")
+	terminationDateStrPtr := flag.String("termination-date", "", "Simulate leaving the company on this date (YYYY-MM-DD): truncates vesting and forfeits unvested shares")
+	pteDaysPtr := flag.Int("pte-days", defaultPostTerminationExerciseDays, "Days after -termination-date before vested-but-unexercised options lapse, unless overridden per-grant")
+	earlyExercisePtr := flag.Bool("early-exercise", false, "Simulate early-exercising all unvested shares at GrantDate for grants marked EarlyExercisable")
 
 	flag.Parse()
 
@@ -43,12 +59,13 @@ func main() {
 	if *filePathPtr == "" {
 		validationErrors = append(validationErrors, "-file flag is required.")
 	}
-	if *exerciseValuePtr == 0.0 {
+	usingPriceHistory := *priceHistoryFilePtr != ""
+	if !usingPriceHistory && *exerciseValuePtr == 0.0 {
 		// Allow 0, but maybe warn? For now, treat as required if non-zero value expected.
 		// Let's keep the original logic: require a non-zero value.
 		validationErrors = append(validationErrors, "-exercise flag with a non-zero value is required.")
 	}
-	if *endDateStrPtr == "" {
+	if !usingPriceHistory && *endDateStrPtr == "" {
 		validationErrors = append(validationErrors, "-end-date flag is required.")
 	}
 
@@ -61,6 +78,16 @@ func main() {
 		}
 	}
 
+	var terminationDate *time.Time
+	if *terminationDateStrPtr != "" {
+		parsed, err := time.Parse(DateFormat, *terminationDateStrPtr)
+		if err != nil {
+			validationErrors = append(validationErrors, fmt.Sprintf("Invalid format for -termination-date: %v. Use %s.", err, DateFormat))
+		} else {
+			terminationDate = &parsed
+		}
+	}
+
 	if len(validationErrors) > 0 {
 		fmt.Println("Errors:")
 		for _, msg := range validationErrors {
@@ -78,6 +105,19 @@ func main() {
 		return
 	}
 
+	if usingPriceHistory {
+		prices, err := loadPriceHistory(*priceHistoryFilePtr)
+		if err != nil {
+			fmt.Println("Error loading price history:", err)
+			return
+		}
+		series := computeTimeSeries(grants, prices)
+		if err := printTimeSeries(series, *outputFormatPtr); err != nil {
+			fmt.Println("Error printing time series:", err)
+		}
+		return
+	}
+
 	fmt.Printf("\nVesting Status as of %s (Exercise Value: $%.2f):\n", endDate.Format(DateFormat), *exerciseValuePtr)
 	// Print header once
 	fmt.Printf("\n%-20s %-12s %-14s %-20s\n", "Grant Name", "Vesting Date", "Total Vested", "Accumulated Value")
@@ -86,7 +126,7 @@ func main() {
 	totalVestedSharesByEndDate := 0
 	totalAccumulatedValue := 0.0
 	for _, grant := range grants {
-		schedule := calculateVestingSchedule(grant)
+		schedule := effectiveSchedule(grant, *earlyExercisePtr)
 		vestedSharesByEndDate, accumulatedValue := printLatestVestingEventBefore(grant.Name, schedule, grant.StrikePrice, *exerciseValuePtr, endDate)
 		totalVestedSharesByEndDate += vestedSharesByEndDate
 		totalAccumulatedValue += accumulatedValue
@@ -102,11 +142,45 @@ func main() {
 	if *printVestingSchedulePtr {
 		fmt.Println()
 		for _, grant := range grants {
-			schedule := calculateVestingSchedule(grant)
+			schedule := effectiveSchedule(grant, *earlyExercisePtr)
 			printVestingSchedule(schedule, grant.StrikePrice, *exerciseValuePtr) // Print each schedule with zero strike price
 			fmt.Println()
 		}
 	}
+
+	if *transactionsFilePtr != "" {
+		transactions, err := loadTransactions(*transactionsFilePtr)
+		if err != nil {
+			fmt.Println("Error loading transactions:", err)
+			return
+		}
+
+		exerciseRecords, saleRecords, err := reconcileTransactions(grants, transactions, *holdingPeriodDaysPtr)
+		if err != nil {
+			fmt.Println("Error reconciling transactions:", err)
+			return
+		}
+
+		printLotLedger(exerciseRecords, saleRecords)
+		printAnnualSummary(exerciseRecords, saleRecords)
+		printRealizedVsUnrealized(grants, exerciseRecords, saleRecords, *exerciseValuePtr, endDate)
+	}
+
+	if *statsPtr {
+		stats := computePortfolioStats(grants, *exerciseValuePtr, endDate)
+		if err := printPortfolioStats(stats, *statsFormatPtr); err != nil {
+			fmt.Println("Error printing stats:", err)
+		}
+	}
+
+	if terminationDate != nil || *earlyExercisePtr {
+		scenarios := make([]GrantScenario, len(grants))
+		for i, grant := range grants {
+			schedule := calculateVestingSchedule(grant)
+			scenarios[i] = computeGrantScenario(grant, schedule, endDate, terminationDate, *pteDaysPtr, *earlyExercisePtr)
+		}
+		printScenarioSummary(scenarios)
+	}
 }
 
 func loadGrants(filePath string) ([]Grant, error) {
@@ -136,8 +210,8 @@ func loadGrants(filePath string) ([]Grant, error) {
 			continue // Skip the header row
 		}
 
-		if len(record) != 6 {
-			return nil, fmt.Errorf("invalid number of columns in CSV row %d (expected 6): %v", lineNumber, record)
+		if len(record) < 6 || len(record) > 9 {
+			return nil, fmt.Errorf("invalid number of columns in CSV row %d (expected 6 to 9): %v", lineNumber, record)
 		}
 
 		// Trim spaces from all fields
@@ -175,13 +249,43 @@ func loadGrants(filePath string) ([]Grant, error) {
 			return nil, fmt.Errorf("invalid grant date format on line %d ('%s', expected %s): %w", lineNumber, record[5], DateFormat, err)
 		}
 
+		scheduleType := defaultScheduleType
+		if len(record) >= 7 && record[6] != "" {
+			scheduleType = ScheduleType(record[6])
+			switch scheduleType {
+			case ScheduleGraded, ScheduleContinuous, ScheduleDelayed, ScheduleCliff:
+				// recognized
+			default:
+				return nil, fmt.Errorf("invalid schedule type on line %d ('%s')", lineNumber, record[6])
+			}
+		}
+
+		postTerminationExerciseDays := 0
+		if len(record) >= 8 && record[7] != "" {
+			postTerminationExerciseDays, err = strconv.Atoi(record[7])
+			if err != nil {
+				return nil, fmt.Errorf("invalid post-termination exercise days on line %d ('%s'): %w", lineNumber, record[7], err)
+			}
+		}
+
+		earlyExercisable := false
+		if len(record) == 9 && record[8] != "" {
+			earlyExercisable, err = strconv.ParseBool(record[8])
+			if err != nil {
+				return nil, fmt.Errorf("invalid early-exercisable flag on line %d ('%s'): %w", lineNumber, record[8], err)
+			}
+		}
+
 		grant := Grant{
-			Name:          name,
-			Shares:        shares,
-			StrikePrice:   strikePrice,
-			CliffMonths:   cliffDuration,
-			VestingMonths: vestingDuration,
-			GrantDate:     grantDate,
+			Name:                        name,
+			Shares:                      shares,
+			StrikePrice:                 strikePrice,
+			CliffMonths:                 cliffDuration,
+			VestingMonths:               vestingDuration,
+			GrantDate:                   grantDate,
+			ScheduleType:                scheduleType,
+			PostTerminationExerciseDays: postTerminationExerciseDays,
+			EarlyExercisable:            earlyExercisable,
 		}
 		grants = append(grants, grant)
 	}
@@ -189,34 +293,10 @@ func loadGrants(filePath string) ([]Grant, error) {
 	return grants, nil
 }
 
+// calculateVestingSchedule computes the vesting events for a grant using the
+// VestingScheduler selected by grant.ScheduleType.
 func calculateVestingSchedule(grant Grant) []VestingEvent {
-	var schedule []VestingEvent
-	vestingInterval := time.Hour * 24 * 30 // Assume monthly vesting for simplicity
-	totalVestingMonths := grant.VestingMonths
-	sharesPerInterval := grant.Shares / totalVestingMonths
-
-	accumulatedShares := grant.CliffMonths * sharesPerInterval
-	if grant.CliffMonths > 0 {
-		schedule = append(schedule, VestingEvent{
-			Date:         grant.GrantDate.AddDate(0, grant.CliffMonths, 0),
-			VestedShares: accumulatedShares,
-		})
-	}
-	for i := grant.CliffMonths + 1; i <= totalVestingMonths; i++ {
-		// BUG the vesting date is typically the same day every month, not 30 days later.
-		vestingDate := grant.GrantDate.Add(time.Duration(i) * vestingInterval)
-		vestedShares := sharesPerInterval
-		if i == totalVestingMonths {
-			vestedShares = grant.Shares - accumulatedShares // Ensure all shares are vested by the end
-		}
-		accumulatedShares += vestedShares
-		schedule = append(schedule, VestingEvent{
-			Date:         vestingDate,
-			VestedShares: vestedShares,
-		})
-	}
-
-	return schedule
+	return schedulerFor(grant.ScheduleType).Schedule(grant)
 }
 
 // printLatestVestingEventBefore finds the latest vesting event on or before the endDate